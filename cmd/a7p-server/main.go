@@ -0,0 +1,33 @@
+// Command a7p-server serves .a7p profiles over HTTPS for the companion
+// mobile app: listing, reading, writing, and deleting individual
+// profiles, bulk zip import/export, and multipart batch upload.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/jaremko/a7p_transfer_example/internal/httpapi"
+	"github.com/jaremko/a7p_transfer_example/internal/upstream"
+)
+
+func main() {
+	dirPtr := flag.String("dir", ".", "directory to serve")
+	certPtr := flag.String("cert", "cert.pem", "path to the certificate file")
+	keyPtr := flag.String("key", "key.pem", "path to the key file")
+	authBackendPtr := flag.String("auth-backend", "", "URL of an auth backend to check every /files, /filelist and /archive request against; unset means no auth")
+	maxUploadBytesPtr := flag.Int64("max-upload-bytes", 10<<20, "maximum size in bytes accepted per POST /files multipart upload or PUT /archive upload")
+
+	flag.Parse()
+
+	log.Printf("Starting localhost server at https://localhost/")
+	log.Printf("You might want to integrate https://github.com/FiloSottile/mkcert")
+	log.Printf("If \"unknown certificate message\" annoys you too much.")
+
+	api := httpapi.New(*authBackendPtr, *maxUploadBytesPtr)
+	router := upstream.NewRouter()
+	api.Register(router, *dirPtr)
+
+	log.Fatal(http.ListenAndServeTLS(":443", *certPtr, *keyPtr, router))
+}
@@ -0,0 +1,76 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func TestWriteReadManifestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	want := []ManifestEntry{
+		{Name: "a.a7p", Size: 42, MD5: "deadbeef"},
+		{Name: "b.a7p", Size: 7, MD5: "cafef00d"},
+	}
+	if err := WriteManifest(zw, want); err != nil {
+		t.Fatalf("WriteManifest: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("closing zip writer: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+	if zr.File[0].Name != ManifestName {
+		t.Fatalf("manifest is not the first entry: got %q", zr.File[0].Name)
+	}
+
+	got, err := ReadManifest(zr)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestExtractEntry(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("profile.a7p")
+	if err != nil {
+		t.Fatalf("zw.Create: %v", err)
+	}
+	if _, err := w.Write([]byte("raw profile bytes")); err != nil {
+		t.Fatalf("writing entry: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("closing zip writer: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := ExtractEntry(zr, "profile.a7p", &out); err != nil {
+		t.Fatalf("ExtractEntry: %v", err)
+	}
+	if out.String() != "raw profile bytes" {
+		t.Errorf("extracted %q, want %q", out.String(), "raw profile bytes")
+	}
+
+	if err := ExtractEntry(zr, "missing.a7p", &out); err == nil {
+		t.Error("ExtractEntry of a missing entry should error")
+	}
+}
@@ -0,0 +1,28 @@
+package archive
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+)
+
+// ExtractEntry streams the named entry from zr to w without buffering the
+// rest of the archive, so a future companion tool can serve one profile
+// directly out of an uploaded archive without re-unpacking it to disk.
+func ExtractEntry(zr *zip.Reader, name string, w io.Writer) error {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", name, err)
+		}
+		defer rc.Close()
+		if _, err := io.Copy(w, rc); err != nil {
+			return fmt.Errorf("copying %s: %w", name, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("entry %s not found in archive", name)
+}
@@ -0,0 +1,59 @@
+// Package archive implements the bulk zip format used by the GET/PUT
+// /archive endpoints: a manifest entry followed by one raw,
+// checksum-prefixed .a7p file per entry. Metadata enumeration (this file)
+// is split from single-entry extraction (cat.go) so a caller that only
+// wants the file list never has to read past it, and a caller that only
+// wants one profile never has to buffer the rest.
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+)
+
+// ManifestName is the name of the first entry in every archive produced by
+// WriteManifest, so a caller that already has a *zip.Reader can recover the
+// full file list by opening this one entry instead of the rest of the
+// archive.
+const ManifestName = "manifest.json"
+
+// ManifestEntry describes one .a7p file packed into an archive.
+type ManifestEntry struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	MD5  string `json:"md5"`
+}
+
+// ReadManifest reads the manifest entry out of an already-opened zip reader.
+func ReadManifest(zr *zip.Reader) ([]ManifestEntry, error) {
+	for _, f := range zr.File {
+		if f.Name != ManifestName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("opening manifest: %w", err)
+		}
+		defer rc.Close()
+		var entries []ManifestEntry
+		if err := json.NewDecoder(rc).Decode(&entries); err != nil {
+			return nil, fmt.Errorf("decoding manifest: %w", err)
+		}
+		return entries, nil
+	}
+	return nil, fmt.Errorf("archive has no %s entry", ManifestName)
+}
+
+// WriteManifest writes entries as the first entry of the archive, named
+// ManifestName.
+func WriteManifest(zw *zip.Writer, entries []ManifestEntry) error {
+	w, err := zw.Create(ManifestName)
+	if err != nil {
+		return fmt.Errorf("creating manifest entry: %w", err)
+	}
+	if entries == nil {
+		entries = []ManifestEntry{}
+	}
+	return json.NewEncoder(w).Encode(entries)
+}
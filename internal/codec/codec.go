@@ -0,0 +1,38 @@
+// Package codec converts between the wire formats a profile can arrive
+// in - protobuf bytes and jsonpb-encoded JSON - and profedit.Payload.
+package codec
+
+import (
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/jaremko/a7p_transfer_example/profedit"
+)
+
+// FromJSON unmarshals a jsonpb-encoded profedit.Payload.
+func FromJSON(jsonStr string) (*profedit.Payload, error) {
+	pb := &profedit.Payload{}
+	if err := jsonpb.UnmarshalString(jsonStr, pb); err != nil {
+		return nil, err
+	}
+	return pb, nil
+}
+
+// ToJSON marshals pb to JSON, emitting default field values.
+func ToJSON(pb *profedit.Payload) (string, error) {
+	marshaler := jsonpb.Marshaler{EmitDefaults: true}
+	return marshaler.MarshalToString(pb)
+}
+
+// Unmarshal decodes raw protobuf bytes into a profedit.Payload.
+func Unmarshal(data []byte) (*profedit.Payload, error) {
+	pb := &profedit.Payload{}
+	if err := proto.Unmarshal(data, pb); err != nil {
+		return nil, err
+	}
+	return pb, nil
+}
+
+// Marshal encodes pb to protobuf bytes.
+func Marshal(pb *profedit.Payload) ([]byte, error) {
+	return proto.Marshal(pb)
+}
@@ -0,0 +1,33 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/jaremko/a7p_transfer_example/profedit"
+)
+
+func TestMarshalUnmarshalRoundTrip(t *testing.T) {
+	data, err := Marshal(&profedit.Payload{})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if _, err := Unmarshal(data); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+}
+
+func TestToJSONFromJSONRoundTrip(t *testing.T) {
+	jsonStr, err := ToJSON(&profedit.Payload{})
+	if err != nil {
+		t.Fatalf("ToJSON: %v", err)
+	}
+	if _, err := FromJSON(jsonStr); err != nil {
+		t.Fatalf("FromJSON(%q): %v", jsonStr, err)
+	}
+}
+
+func TestFromJSONRejectsInvalidJSON(t *testing.T) {
+	if _, err := FromJSON("not json"); err == nil {
+		t.Error("FromJSON should reject invalid JSON")
+	}
+}
@@ -0,0 +1,177 @@
+package httpapi
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/jaremko/a7p_transfer_example/internal/archive"
+	"github.com/jaremko/a7p_transfer_example/internal/codec"
+	"github.com/jaremko/a7p_transfer_example/internal/store"
+)
+
+// GetArchive streams every .a7p in dir as a single zip archive. The
+// manifest is written first so archive.ReadManifest can recover the file
+// list by opening that one entry instead of the rest of the archive; every
+// other entry carries its on-disk bytes unchanged, checksum prefix
+// included, so tools that expect the on-disk format keep working against
+// it.
+func (a *API) GetArchive(dir string, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	files, err := a.Store.List(dir)
+	if err != nil {
+		log.Printf("Error reading directory: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+
+	type entry struct {
+		name string
+		data []byte
+	}
+	var entries []entry
+	var manifest []archive.ManifestEntry
+	for _, file := range files {
+		data, err := a.Store.Read(dir, file.Name())
+		if err != nil {
+			log.Printf("Error reading file %s: %v", file.Name(), err)
+			respondWithError(w, http.StatusInternalServerError, "Server error")
+			return
+		}
+		if len(data) <= store.ChecksumLength {
+			log.Printf("Skipping %s: too short for a checksum", file.Name())
+			continue
+		}
+		entries = append(entries, entry{name: file.Name(), data: data})
+		manifest = append(manifest, archive.ManifestEntry{
+			Name: file.Name(),
+			Size: int64(len(data)),
+			MD5:  string(data[:store.ChecksumLength]),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="profiles.zip"`)
+
+	zw := zip.NewWriter(w)
+	defer zw.Close()
+
+	if err := archive.WriteManifest(zw, manifest); err != nil {
+		log.Printf("Error writing archive manifest: %v", err)
+		return
+	}
+	for _, e := range entries {
+		ew, err := zw.Create(e.name)
+		if err != nil {
+			log.Printf("Error creating archive entry %s: %v", e.name, err)
+			return
+		}
+		if _, err := ew.Write(e.data); err != nil {
+			log.Printf("Error writing archive entry %s: %v", e.name, err)
+			return
+		}
+	}
+}
+
+// PutArchive accepts a zip archive in the GetArchive format and writes it
+// back into dir. Every entry is validated - filename, checksum, and
+// proto unmarshal - before anything is written, so a single bad entry
+// rejects the whole archive instead of partially importing it.
+func (a *API) PutArchive(dir string, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, a.MaxUploadBytes))
+	if err != nil {
+		log.Printf("Error reading archive upload: %v", err)
+		respondWithError(w, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		log.Printf("Error reading zip archive: %v", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid archive")
+		return
+	}
+
+	type validated struct {
+		name string
+		data []byte
+	}
+	var files []validated
+	for _, f := range zr.File {
+		if f.Name == archive.ManifestName {
+			continue
+		}
+		filename, err := store.SanitizeFilename(f.Name)
+		if err != nil {
+			log.Printf("Invalid filename in archive: %v", err)
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("invalid filename %q in archive", f.Name))
+			return
+		}
+
+		if int64(f.UncompressedSize64) > a.MaxUploadBytes {
+			log.Printf("Archive entry %s too large: %d bytes", f.Name, f.UncompressedSize64)
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("entry %s too large", f.Name))
+			return
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			log.Printf("Error opening archive entry %s: %v", f.Name, err)
+			respondWithError(w, http.StatusBadRequest, "Invalid archive")
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, a.MaxUploadBytes+1))
+		rc.Close()
+		if err != nil {
+			log.Printf("Error reading archive entry %s: %v", f.Name, err)
+			respondWithError(w, http.StatusBadRequest, "Invalid archive")
+			return
+		}
+		if int64(len(data)) > a.MaxUploadBytes {
+			log.Printf("Archive entry %s too large: exceeded %d bytes", f.Name, a.MaxUploadBytes)
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("entry %s too large", f.Name))
+			return
+		}
+
+		content, err := store.ValidateAndStrip(data)
+		if err != nil {
+			log.Printf("Checksum validation failed for %s: %v", filename, err)
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("checksum mismatch for %s", filename))
+			return
+		}
+
+		if _, err := codec.Unmarshal(content); err != nil {
+			log.Printf("Proto validation failed for %s: %v", filename, err)
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("invalid profile %s", filename))
+			return
+		}
+
+		files = append(files, validated{name: filename, data: data})
+	}
+
+	for _, f := range files {
+		unlock := a.Store.Lock(dir, f.name)
+		err := a.Store.AtomicWrite(dir, f.name, f.data)
+		unlock()
+		if err != nil {
+			log.Printf("Error writing %s from archive: %v", f.name, err)
+			respondWithError(w, http.StatusInternalServerError, "Server error")
+			return
+		}
+		a.Cache.Invalidate(f.name)
+	}
+
+	w.Write([]byte("OK"))
+}
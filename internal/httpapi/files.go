@@ -0,0 +1,299 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/jaremko/a7p_transfer_example/internal/codec"
+	"github.com/jaremko/a7p_transfer_example/internal/httpcache"
+	"github.com/jaremko/a7p_transfer_example/internal/store"
+)
+
+// FileList responds with the names of every .a7p in dir, and serves
+// 304 Not Modified when the client's If-None-Match matches the listing's
+// current ETag.
+func (a *API) FileList(dir string, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	files, err := a.Store.List(dir)
+	if err != nil {
+		log.Printf("Error reading directory: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+
+	etag, err := a.Store.ListETag(dir, files)
+	if err != nil {
+		log.Printf("Error computing file list ETag: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+	if httpcache.CheckETag(w, r, etag) {
+		return
+	}
+
+	fileNames := make([]string, 0, len(files))
+	for _, file := range files {
+		fileNames = append(fileNames, file.Name())
+	}
+
+	fileListJson, err := json.Marshal(fileNames)
+	if err != nil {
+		log.Printf("Error marshalling file list to JSON: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+
+	w.Write(fileListJson)
+}
+
+// GetFile responds with the JSON-decoded profile named by the "filename"
+// query parameter, serving 304 Not Modified when the client's
+// If-None-Match matches the file's checksum, and a cached decode when
+// available. Both checks are done against the checksum prefix alone, so a
+// repeated poll that hits either one never reads the rest of the file off
+// disk, let alone decodes it.
+func (a *API) GetFile(dir string, w http.ResponseWriter, r *http.Request) {
+	filename, err := store.SanitizeFilename(r.URL.Query().Get("filename"))
+	if err != nil {
+		log.Printf("Invalid filename: %v", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid filename")
+		return
+	}
+
+	etag, err := a.Store.ReadChecksumPrefix(dir, filename)
+	if err != nil {
+		log.Printf("Error reading file: %v", err)
+		respondWithError(w, http.StatusNotFound, "File not found")
+		return
+	}
+	if httpcache.CheckETag(w, r, etag) {
+		return
+	}
+	if cached, ok := a.Cache.Get(filename, etag); ok {
+		w.Write(cached)
+		return
+	}
+
+	data, err := a.Store.Read(dir, filename)
+	if err != nil {
+		log.Printf("Error reading file: %v", err)
+		respondWithError(w, http.StatusNotFound, "File not found")
+		return
+	}
+
+	content, err := store.ValidateAndStrip(data)
+	if err != nil {
+		log.Printf("Error validating or stripping checksum: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+
+	pb, err := codec.Unmarshal(content)
+	if err != nil {
+		log.Printf("Error unmarshalling proto file: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+
+	jsonStr, err := codec.ToJSON(pb)
+	if err != nil {
+		log.Printf("Error marshalling proto file to json: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+
+	jsonBytes := []byte(jsonStr)
+	a.Cache.Put(filename, etag, jsonBytes)
+	w.Write(jsonBytes)
+}
+
+// PutFile decodes a JSON-encoded profile from the request body and
+// writes it, checksum-framed, to the file named by the "filename" query
+// parameter.
+func (a *API) PutFile(dir string, w http.ResponseWriter, r *http.Request) {
+	filename, err := store.SanitizeFilename(r.URL.Query().Get("filename"))
+	if err != nil {
+		log.Printf("Invalid filename: %v", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid filename")
+		return
+	}
+
+	var req struct {
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Error decoding request body: %v", err)
+		respondWithError(w, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	pb, err := codec.FromJSON(string(req.Content))
+	if err != nil {
+		log.Printf("Error unmarshalling json to proto: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+
+	content, err := codec.Marshal(pb)
+	if err != nil {
+		log.Printf("Error marshalling proto to bytes: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+
+	unlock := a.Store.Lock(dir, filename)
+	defer unlock()
+
+	if err := a.Store.Write(dir, filename, content); err != nil {
+		log.Printf("Error writing file: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+	a.Cache.Invalidate(filename)
+
+	w.Write([]byte("OK"))
+}
+
+// DeleteFile removes the file named by the "filename" query parameter.
+func (a *API) DeleteFile(dir string, w http.ResponseWriter, r *http.Request) {
+	filename, err := store.SanitizeFilename(r.URL.Query().Get("filename"))
+	if err != nil {
+		log.Printf("Invalid filename: %v", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid filename")
+		return
+	}
+
+	unlock := a.Store.Lock(dir, filename)
+	defer unlock()
+
+	if err := a.Store.Delete(dir, filename); err != nil {
+		log.Printf("Error deleting file: %v", err)
+		respondWithError(w, http.StatusNotFound, "File not found")
+		return
+	}
+	a.Cache.Invalidate(filename)
+
+	w.Write([]byte("OK"))
+}
+
+// uploadResult is one element of the JSON body returned by PostFiles, in
+// the style of the pomf upload API: a successful part carries
+// hash/size/url, a failed one carries error, and the rest of the batch
+// still commits.
+type uploadResult struct {
+	Name  string `json:"name"`
+	Hash  string `json:"hash,omitempty"`
+	Size  int64  `json:"size,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// PostFiles accepts a multipart/form-data upload with any number of file
+// parts. Each part is either a raw, already checksum-prefixed .a7p (any
+// Content-Type other than application/json) or a bare JSON payload
+// (Content-Type: application/json), which is wrapped to proto and
+// checksum-prefixed before being written. A per-file failure is reported
+// in that file's result entry without aborting the rest of the batch.
+func (a *API) PostFiles(dir string, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, a.MaxUploadBytes)
+	if err := r.ParseMultipartForm(a.MaxUploadBytes); err != nil {
+		log.Printf("Error parsing multipart upload: %v", err)
+		respondWithError(w, http.StatusBadRequest, "Bad request")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	var results []uploadResult
+	for _, headers := range r.MultipartForm.File {
+		for _, fh := range headers {
+			results = append(results, a.uploadPart(dir, fh))
+		}
+	}
+
+	resp := struct {
+		Success bool           `json:"success"`
+		Files   []uploadResult `json:"files"`
+	}{Success: true, Files: results}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		log.Printf("Error marshalling upload response: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// uploadPart validates and writes a single multipart file part, returning
+// its result entry. It never returns an error itself - any failure is
+// reported through uploadResult.Error so the caller can keep processing
+// the rest of the batch.
+func (a *API) uploadPart(dir string, fh *multipart.FileHeader) uploadResult {
+	filename, err := store.SanitizeFilename(fh.Filename)
+	if err != nil {
+		return uploadResult{Name: fh.Filename, Error: "invalid filename"}
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return uploadResult{Name: filename, Error: "could not open upload"}
+	}
+	defer f.Close()
+
+	raw, err := io.ReadAll(f)
+	if err != nil {
+		return uploadResult{Name: filename, Error: "could not read upload"}
+	}
+
+	var data []byte
+	if fh.Header.Get("Content-Type") == "application/json" {
+		pb, err := codec.FromJSON(string(raw))
+		if err != nil {
+			return uploadResult{Name: filename, Error: "invalid JSON payload"}
+		}
+		content, err := codec.Marshal(pb)
+		if err != nil {
+			return uploadResult{Name: filename, Error: "could not marshal payload"}
+		}
+		data = store.Frame(content)
+	} else {
+		content, err := store.ValidateAndStrip(raw)
+		if err != nil {
+			return uploadResult{Name: filename, Error: err.Error()}
+		}
+		if _, err := codec.Unmarshal(content); err != nil {
+			return uploadResult{Name: filename, Error: "invalid profile"}
+		}
+		data = raw
+	}
+
+	unlock := a.Store.Lock(dir, filename)
+	defer unlock()
+
+	if err := a.Store.AtomicWrite(dir, filename, data); err != nil {
+		log.Printf("Error writing %s from upload: %v", filename, err)
+		return uploadResult{Name: filename, Error: "could not write file"}
+	}
+	a.Cache.Invalidate(filename)
+
+	return uploadResult{
+		Name: filename,
+		Hash: string(data[:32]),
+		Size: int64(len(data)),
+		URL:  "/files?filename=" + url.QueryEscape(filename),
+	}
+}
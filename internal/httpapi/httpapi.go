@@ -0,0 +1,165 @@
+// Package httpapi implements the file server's HTTP handlers as
+// http.Handler values built around internal/store and internal/codec,
+// so internal/upstream can wire them into a route table.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jaremko/a7p_transfer_example/internal/httpcache"
+	"github.com/jaremko/a7p_transfer_example/internal/store"
+	"github.com/jaremko/a7p_transfer_example/internal/upstream"
+)
+
+// authBackendTimeout bounds how long authMiddleware waits on a.AuthBackend
+// before giving up, so a slow or wedged backend can't hang the serving
+// goroutine for every dir-scoped request.
+const authBackendTimeout = 10 * time.Second
+
+// authBackendClient is dedicated to auth backend passthrough requests -
+// http.DefaultClient has no timeout and must not be used here.
+var authBackendClient = &http.Client{Timeout: authBackendTimeout}
+
+// API holds the state shared across handlers: the profile store, the
+// decoded-JSON cache, and the optional auth backend / upload limit
+// configuration.
+type API struct {
+	Store          *store.Store
+	Cache          *httpcache.Cache
+	AuthBackend    string
+	MaxUploadBytes int64
+}
+
+// New creates an API ready to Register against a Router.
+func New(authBackend string, maxUploadBytes int64) *API {
+	return &API{
+		Store:          store.New(),
+		Cache:          httpcache.NewCache(256),
+		AuthBackend:    authBackend,
+		MaxUploadBytes: maxUploadBytes,
+	}
+}
+
+// dirHandler is a handler that needs to know which directory to operate
+// against. authMiddleware resolves it per request - scoped per-tenant
+// when an auth backend is configured - before calling through.
+type dirHandler func(dir string, w http.ResponseWriter, r *http.Request)
+
+// Register wires every endpoint into r, rooted at baseDir. Every
+// dir-scoped endpoint goes through authMiddleware - including /filelist
+// and /archive, which read or write every profile in dir at once - so an
+// operator-configured -auth-backend covers the whole store, not just
+// /files.
+func (a *API) Register(r *upstream.Router, baseDir string) {
+	r.Add(http.MethodGet, `^/filelist$`, a.authMiddleware(baseDir, a.FileList))
+	r.Add(http.MethodGet, `^/files$`, a.authMiddleware(baseDir, a.GetFile))
+	r.Add(http.MethodPut, `^/files$`, a.authMiddleware(baseDir, a.PutFile))
+	r.Add(http.MethodDelete, `^/files$`, a.authMiddleware(baseDir, a.DeleteFile))
+	r.Add(http.MethodPost, `^/files$`, a.authMiddleware(baseDir, a.PostFiles))
+	r.Add(http.MethodGet, `^/archive$`, a.authMiddleware(baseDir, a.GetArchive))
+	r.Add(http.MethodPut, `^/archive$`, a.authMiddleware(baseDir, a.PutArchive))
+	r.Add("", `^/.*$`, http.HandlerFunc(a.StaticFiles))
+}
+
+func respondWithError(w http.ResponseWriter, code int, message string) {
+	resp, _ := json.Marshal(map[string]string{"error": message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(resp)
+}
+
+// StaticFiles serves the requested path directly off the working
+// directory, for the web UI and any other static assets.
+func (a *API) StaticFiles(w http.ResponseWriter, r *http.Request) {
+	http.ServeFile(w, r, r.URL.Path[1:])
+}
+
+// forwardHeadersHeader is the response header an auth backend uses to
+// tell authMiddleware which of its own response headers to splice into
+// the downstream request - e.g. an authenticated user id or a per-user
+// subdirectory name.
+const forwardHeadersHeader = "X-A7p-Forward-Headers"
+
+// userHeader, when forwarded by the auth backend, scopes dir to a
+// per-user subdirectory so one server can host multiple tenants.
+const userHeader = "X-A7p-User"
+
+// authMiddleware mirrors the incoming request to a.AuthBackend with an
+// empty body - method, path, and headers (especially Authorization and
+// Cookie) intact - and only calls next once the backend answers 2xx.
+// Response headers the backend names in X-A7p-Forward-Headers are copied
+// onto the downstream request, and a forwarded X-A7p-User scopes dir to a
+// per-user subdirectory. When a.AuthBackend is empty, next runs
+// unconditionally against baseDir.
+func (a *API) authMiddleware(baseDir string, next dirHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.AuthBackend == "" {
+			next(baseDir, w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), authBackendTimeout)
+		defer cancel()
+
+		passthroughReq, err := http.NewRequestWithContext(ctx, r.Method, a.AuthBackend+r.URL.RequestURI(), nil)
+		if err != nil {
+			log.Printf("Error building auth backend request: %v", err)
+			respondWithError(w, http.StatusInternalServerError, "Server error")
+			return
+		}
+		for name, values := range r.Header {
+			for _, v := range values {
+				passthroughReq.Header.Add(name, v)
+			}
+		}
+
+		resp, err := authBackendClient.Do(passthroughReq)
+		if err != nil {
+			log.Printf("Error reaching auth backend: %v", err)
+			respondWithError(w, http.StatusBadGateway, "Auth backend unreachable")
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		dir := baseDir
+		for _, name := range strings.Split(resp.Header.Get(forwardHeadersHeader), ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			value := resp.Header.Get(name)
+			r.Header.Set(name, value)
+
+			if strings.EqualFold(name, userHeader) && value != "" {
+				if strings.Contains(value, "..") || strings.ContainsAny(value, `/\`) {
+					log.Printf("Invalid %s from auth backend: %q", userHeader, value)
+					respondWithError(w, http.StatusInternalServerError, "Server error")
+					return
+				}
+				dir = filepath.Join(baseDir, value)
+			}
+		}
+
+		if dir != baseDir {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				log.Printf("Error creating per-user directory %s: %v", dir, err)
+				respondWithError(w, http.StatusInternalServerError, "Server error")
+				return
+			}
+		}
+
+		next(dir, w, r)
+	})
+}
@@ -0,0 +1,316 @@
+package httpapi_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaremko/a7p_transfer_example/internal/archive"
+	"github.com/jaremko/a7p_transfer_example/internal/httpapi"
+	"github.com/jaremko/a7p_transfer_example/internal/store"
+	"github.com/jaremko/a7p_transfer_example/internal/upstream"
+)
+
+// newTestServer builds an httpapi.API rooted at a fresh temp directory and
+// serves it via internal/upstream, the same wiring cmd/a7p-server uses.
+func newTestServer(t *testing.T, authBackend string, maxUploadBytes int64) (*httptest.Server, string, *httpapi.API) {
+	t.Helper()
+	dir := t.TempDir()
+	api := httpapi.New(authBackend, maxUploadBytes)
+	router := upstream.NewRouter()
+	api.Register(router, dir)
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv, dir, api
+}
+
+func TestFileListRoundTrip(t *testing.T) {
+	srv, dir, _ := newTestServer(t, "", 10<<20)
+	s := store.New()
+	if err := s.Write(dir, "a.a7p", []byte("a-content")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := s.Write(dir, "b.a7p", []byte("b-content")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	resp, err := http.Get(srv.URL + "/filelist")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var names []string
+	if err := json.NewDecoder(resp.Body).Decode(&names); err != nil {
+		t.Fatalf("decoding file list: %v", err)
+	}
+	if len(names) != 2 {
+		t.Fatalf("file list = %v, want 2 entries", names)
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("response has no ETag")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/filelist", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp2, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusNotModified {
+		t.Errorf("status with matching If-None-Match = %d, want 304", resp2.StatusCode)
+	}
+}
+
+func TestGetFileNotFound(t *testing.T) {
+	srv, _, _ := newTestServer(t, "", 10<<20)
+
+	resp, err := http.Get(srv.URL + "/files?filename=missing.a7p")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestGetFileServesCachedDecodeAndETag(t *testing.T) {
+	srv, dir, api := newTestServer(t, "", 10<<20)
+	s := store.New()
+	content := []byte("raw on-disk payload")
+	if err := s.Write(dir, "a.a7p", content); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	etag := store.Checksum(content)
+	api.Cache.Put("a.a7p", etag, []byte(`{"cached":true}`))
+
+	resp, err := http.Get(srv.URL + "/files?filename=a.a7p")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+	if string(body) != `{"cached":true}` {
+		t.Errorf("body = %q, want the cached decode", body)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/files?filename=a.a7p", nil)
+	req.Header.Set("If-None-Match", `"`+etag+`"`)
+	resp2, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusNotModified {
+		t.Errorf("status with matching If-None-Match = %d, want 304", resp2.StatusCode)
+	}
+}
+
+func TestPutFileInvalidFilenameRejected(t *testing.T) {
+	srv, _, _ := newTestServer(t, "", 10<<20)
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/files?filename=profile.json", bytes.NewReader([]byte(`{"content":{}}`)))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestDeleteFileNotFound(t *testing.T) {
+	srv, _, _ := newTestServer(t, "", 10<<20)
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/files?filename=missing.a7p", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+}
+
+// TestAuthMiddlewareCoversEveryDirScopedEndpoint guards against the gap
+// where /filelist and /archive bypassed the auth backend passthrough
+// while /files didn't: with an unauthorized backend, every endpoint that
+// reads or writes the store must refuse the request.
+func TestAuthMiddlewareCoversEveryDirScopedEndpoint(t *testing.T) {
+	authSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer authSrv.Close()
+
+	srv, dir, _ := newTestServer(t, authSrv.URL, 10<<20)
+	s := store.New()
+	if err := s.Write(dir, "a.a7p", []byte("content")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	for _, path := range []string{"/files?filename=a.a7p", "/filelist", "/archive"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("Get %s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("GET %s status = %d, want 401", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestPostFilesInvalidFilenameReportsPerFileError(t *testing.T) {
+	srv, dir, _ := newTestServer(t, "", 10<<20)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "profile.json")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	part.Write([]byte("irrelevant"))
+	mw.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/files", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+		Files   []struct {
+			Name  string `json:"name"`
+			Error string `json:"error"`
+		} `json:"files"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Error == "" {
+		t.Fatalf("response = %+v, want a single entry with Error set", result.Files)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "profile.json")); !os.IsNotExist(err) {
+		t.Errorf("invalid upload should not have been written, stat err = %v", err)
+	}
+}
+
+func TestGetArchiveContainsManifestAndEntries(t *testing.T) {
+	srv, dir, _ := newTestServer(t, "", 10<<20)
+	s := store.New()
+	if err := s.Write(dir, "a.a7p", []byte("a-content")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	resp, err := http.Get(srv.URL + "/archive")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+	if zr.File[0].Name != archive.ManifestName {
+		t.Fatalf("first entry = %q, want the manifest", zr.File[0].Name)
+	}
+
+	manifest, err := archive.ReadManifest(zr)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if len(manifest) != 1 || manifest[0].Name != "a.a7p" {
+		t.Fatalf("manifest = %+v, want a single a.a7p entry", manifest)
+	}
+
+	var extracted bytes.Buffer
+	if err := archive.ExtractEntry(zr, "a.a7p", &extracted); err != nil {
+		t.Fatalf("ExtractEntry: %v", err)
+	}
+	onDisk, err := s.Read(dir, "a.a7p")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if extracted.String() != string(onDisk) {
+		t.Error("archive entry bytes don't match the on-disk file")
+	}
+}
+
+func TestPutArchiveRejectsBadChecksum(t *testing.T) {
+	srv, dir, _ := newTestServer(t, "", 10<<20)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("a.a7p")
+	if err != nil {
+		t.Fatalf("zw.Create: %v", err)
+	}
+	w.Write([]byte("0000000000000000000000000000000garbage"))
+	zw.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/archive", &buf)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.a7p")); !os.IsNotExist(err) {
+		t.Error("a bad entry should not have been written to dir")
+	}
+}
+
+// TestPutArchiveRespectsMaxUploadBytes guards the PUT /archive upload
+// cap: without it, an oversized body would be read into memory in full.
+func TestPutArchiveRespectsMaxUploadBytes(t *testing.T) {
+	srv, _, _ := newTestServer(t, "", 16)
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/archive", bytes.NewReader(make([]byte, 4096)))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+}
@@ -0,0 +1,24 @@
+// Package httpcache implements a small ETag-based caching layer for the
+// file server's read endpoints: an entity tag derived from content the
+// caller already trusts - an on-disk checksum, a digest of a directory
+// listing - lets clients skip the response body entirely via
+// If-None-Match, and the Cache behind it skips the expensive decode work
+// a cache hit would otherwise redo.
+package httpcache
+
+import "net/http"
+
+// CheckETag sets ETag and Cache-Control on w, and, if the request's
+// If-None-Match matches etag, writes a 304 Not Modified and returns true.
+// Callers should do no further work on the response once this returns
+// true.
+func CheckETag(w http.ResponseWriter, r *http.Request, etag string) bool {
+	quoted := `"` + etag + `"`
+	w.Header().Set("ETag", quoted)
+	w.Header().Set("Cache-Control", "private, must-revalidate")
+	if r.Header.Get("If-None-Match") == quoted {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckETagNoneMatch(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/files?filename=a.a7p", nil)
+
+	if CheckETag(w, r, "abc123") {
+		t.Fatal("CheckETag returned true with no If-None-Match header")
+	}
+	if got := w.Header().Get("ETag"); got != `"abc123"` {
+		t.Errorf("ETag header = %q, want %q", got, `"abc123"`)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "private, must-revalidate" {
+		t.Errorf("Cache-Control header = %q", got)
+	}
+}
+
+func TestCheckETagMatch(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/files?filename=a.a7p", nil)
+	r.Header.Set("If-None-Match", `"abc123"`)
+
+	if !CheckETag(w, r, "abc123") {
+		t.Fatal("CheckETag returned false for a matching If-None-Match")
+	}
+	if w.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+}
@@ -0,0 +1,92 @@
+package httpcache
+
+import "sync"
+
+// entryKey identifies one cached decode: a filename plus the md5 of the
+// file content that produced it. Keying on both means a changed file
+// (different md5) is simply a cache miss, without needing every read path
+// to explicitly invalidate - only writers need to call Invalidate.
+type entryKey struct {
+	name string
+	md5  string
+}
+
+// Cache is a small in-process LRU of decoded JSON bytes, keyed by
+// filename and the on-disk md5 that produced them. It lets repeated polls
+// for an unchanged file skip the proto-to-JSON marshal, which dominates
+// request time on low-power hosts.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	order    []entryKey
+	entries  map[entryKey][]byte
+}
+
+// NewCache creates a Cache that holds at most capacity decoded entries.
+func NewCache(capacity int) *Cache {
+	return &Cache{
+		capacity: capacity,
+		entries:  make(map[entryKey][]byte),
+	}
+}
+
+// Get returns the cached JSON bytes for (name, md5), if present.
+func (c *Cache) Get(name, md5 string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := entryKey{name, md5}
+	data, ok := c.entries[key]
+	if ok {
+		c.touch(key)
+	}
+	return data, ok
+}
+
+// Put stores the decoded JSON bytes for (name, md5), evicting the least
+// recently used entry first if the cache is already full.
+func (c *Cache) Put(name, md5 string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := entryKey{name, md5}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.capacity {
+		c.evictOldest()
+	}
+	c.entries[key] = data
+	c.touch(key)
+}
+
+// Invalidate drops every cached entry for name, regardless of md5, so a
+// successful PUT or DELETE never leaves a stale decode behind.
+func (c *Cache) Invalidate(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.name == name {
+			delete(c.entries, key)
+			c.removeFromOrder(key)
+		}
+	}
+}
+
+func (c *Cache) touch(key entryKey) {
+	c.removeFromOrder(key)
+	c.order = append(c.order, key)
+}
+
+func (c *Cache) removeFromOrder(key entryKey) {
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *Cache) evictOldest() {
+	if len(c.order) == 0 {
+		return
+	}
+	oldest := c.order[0]
+	c.order = c.order[1:]
+	delete(c.entries, oldest)
+}
@@ -0,0 +1,48 @@
+package httpcache
+
+import "testing"
+
+func TestCacheGetPut(t *testing.T) {
+	c := NewCache(2)
+
+	if _, ok := c.Get("a.a7p", "md5-a"); ok {
+		t.Fatal("Get on empty cache should miss")
+	}
+
+	c.Put("a.a7p", "md5-a", []byte("decoded-a"))
+	got, ok := c.Get("a.a7p", "md5-a")
+	if !ok || string(got) != "decoded-a" {
+		t.Fatalf("Get(a.a7p, md5-a) = %q, %v", got, ok)
+	}
+
+	if _, ok := c.Get("a.a7p", "md5-b"); ok {
+		t.Fatal("a changed checksum should be a cache miss, not a stale hit")
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewCache(2)
+	c.Put("a.a7p", "1", []byte("a"))
+	c.Put("b.a7p", "1", []byte("b"))
+	c.Put("c.a7p", "1", []byte("c")) // evicts a.a7p, the least recently used
+
+	if _, ok := c.Get("a.a7p", "1"); ok {
+		t.Error("a.a7p should have been evicted")
+	}
+	if _, ok := c.Get("b.a7p", "1"); !ok {
+		t.Error("b.a7p should still be cached")
+	}
+	if _, ok := c.Get("c.a7p", "1"); !ok {
+		t.Error("c.a7p should still be cached")
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	c := NewCache(4)
+	c.Put("a.a7p", "1", []byte("a"))
+	c.Invalidate("a.a7p")
+
+	if _, ok := c.Get("a.a7p", "1"); ok {
+		t.Error("Get after Invalidate should miss")
+	}
+}
@@ -0,0 +1,192 @@
+// Package store implements filesystem CRUD for .a7p profiles, plus the
+// checksum framing every profile is stored with on disk: a 32-byte hex
+// MD5 of the payload, prefixed onto the raw protobuf bytes.
+package store
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ChecksumLength is the size in bytes of the hex MD5 prefix on every
+// on-disk .a7p file.
+const ChecksumLength = 32
+
+// Store provides filesystem CRUD for .a7p profiles rooted at a
+// caller-supplied directory, and hands out per-filename locks so
+// concurrent writers targeting the same profile never interleave.
+type Store struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// New creates an empty Store.
+func New() *Store {
+	return &Store{locks: make(map[string]*sync.Mutex)}
+}
+
+// Lock acquires the per-filename lock for name within dir and returns a
+// function that releases it.
+func (s *Store) Lock(dir, name string) func() {
+	key := filepath.Join(dir, name)
+	s.mu.Lock()
+	m, ok := s.locks[key]
+	if !ok {
+		m = &sync.Mutex{}
+		s.locks[key] = m
+	}
+	s.mu.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
+
+// Checksum returns the hex MD5 digest of data.
+func Checksum(data []byte) string {
+	h := md5.New()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Frame prefixes content with the checksum .a7p files are stored with on
+// disk.
+func Frame(content []byte) []byte {
+	return append([]byte(Checksum(content)), content...)
+}
+
+// ValidateAndStrip checks data's checksum prefix against its content and
+// returns the content with the prefix removed.
+func ValidateAndStrip(data []byte) ([]byte, error) {
+	if len(data) <= ChecksumLength {
+		return nil, fmt.Errorf("data too short for a checksum")
+	}
+	prefix, content := data[:ChecksumLength], data[ChecksumLength:]
+	calculated := Checksum(content)
+	if string(prefix) != calculated {
+		return nil, fmt.Errorf("checksum mismatch: expected %s, got %s", calculated, string(prefix))
+	}
+	return content, nil
+}
+
+// SanitizeFilename rejects path traversal and anything not named *.a7p.
+func SanitizeFilename(filename string) (string, error) {
+	if strings.Contains(filename, "..") || !strings.HasSuffix(filename, ".a7p") {
+		return "", errors.New("invalid filename")
+	}
+	return filename, nil
+}
+
+// List returns the .a7p entries directly inside dir.
+func (s *Store) List(dir string) ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []fs.FileInfo
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".a7p") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, info)
+	}
+	return files, nil
+}
+
+// ReadChecksumPrefix reads just the checksum prefix of an on-disk .a7p
+// file, without reading the protobuf payload behind it.
+func (s *Store) ReadChecksumPrefix(dir, filename string) (string, error) {
+	f, err := os.Open(filepath.Join(dir, filename))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	prefix := make([]byte, ChecksumLength)
+	if _, err := io.ReadFull(f, prefix); err != nil {
+		return "", err
+	}
+	return string(prefix), nil
+}
+
+// Read returns the full on-disk bytes - checksum prefix included - of
+// filename in dir.
+func (s *Store) Read(dir, filename string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(dir, filename))
+}
+
+// Write frames content with its checksum and writes it to filename in
+// dir, matching the original PUT /files behavior.
+func (s *Store) Write(dir, filename string, content []byte) error {
+	return os.WriteFile(filepath.Join(dir, filename), Frame(content), 0644)
+}
+
+// AtomicWrite writes data - already framed with its checksum - to
+// filename inside dir via a temp file plus rename, so a crash mid-write
+// never leaves a truncated or partially written profile in place of an
+// existing one.
+func (s *Store) AtomicWrite(dir, filename string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, filename+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Rename(tmpName, filepath.Join(dir, filename)); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("renaming temp file: %w", err)
+	}
+	return nil
+}
+
+// Delete removes filename from dir.
+func (s *Store) Delete(dir, filename string) error {
+	return os.Remove(filepath.Join(dir, filename))
+}
+
+// ListETag returns an entity tag for a directory listing, derived from
+// the sorted (name, mtime, md5) tuples of files. It changes whenever a
+// file is added, removed, renamed, or its content changes.
+func (s *Store) ListETag(dir string, files []fs.FileInfo) (string, error) {
+	type fileMeta struct {
+		name  string
+		mtime int64
+		md5   string
+	}
+
+	var metas []fileMeta
+	for _, file := range files {
+		prefix, err := s.ReadChecksumPrefix(dir, file.Name())
+		if err != nil {
+			return "", err
+		}
+		metas = append(metas, fileMeta{name: file.Name(), mtime: file.ModTime().UnixNano(), md5: prefix})
+	}
+	sort.Slice(metas, func(i, j int) bool { return metas[i].name < metas[j].name })
+
+	h := md5.New()
+	for _, m := range metas {
+		fmt.Fprintf(h, "%s\x00%d\x00%s\x00", m.name, m.mtime, m.md5)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,136 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFrameValidateAndStripRoundTrip(t *testing.T) {
+	content := []byte("profile bytes")
+	framed := Frame(content)
+
+	if len(framed) != ChecksumLength+len(content) {
+		t.Fatalf("framed length = %d, want %d", len(framed), ChecksumLength+len(content))
+	}
+
+	got, err := ValidateAndStrip(framed)
+	if err != nil {
+		t.Fatalf("ValidateAndStrip: %v", err)
+	}
+	if string(got) != string(content) {
+		t.Errorf("ValidateAndStrip = %q, want %q", got, content)
+	}
+}
+
+func TestValidateAndStripRejectsTamperedData(t *testing.T) {
+	framed := Frame([]byte("profile bytes"))
+	framed[len(framed)-1] ^= 0xFF // corrupt the payload without touching the checksum
+
+	if _, err := ValidateAndStrip(framed); err == nil {
+		t.Error("ValidateAndStrip should reject a payload that doesn't match its checksum")
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	cases := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"profile.a7p", false},
+		{"../etc/passwd", true},
+		{"profile.json", true},
+	}
+	for _, c := range cases {
+		_, err := SanitizeFilename(c.name)
+		if (err != nil) != c.wantErr {
+			t.Errorf("SanitizeFilename(%q) error = %v, wantErr %v", c.name, err, c.wantErr)
+		}
+	}
+}
+
+func TestStoreWriteReadDeleteRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	s := New()
+
+	if err := s.Write(dir, "profile.a7p", []byte("content")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	data, err := s.Read(dir, "profile.a7p")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	content, err := ValidateAndStrip(data)
+	if err != nil {
+		t.Fatalf("ValidateAndStrip: %v", err)
+	}
+	if string(content) != "content" {
+		t.Errorf("round-tripped content = %q, want %q", content, "content")
+	}
+
+	files, err := s.List(dir)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(files) != 1 || files[0].Name() != "profile.a7p" {
+		t.Fatalf("List = %+v, want a single profile.a7p entry", files)
+	}
+
+	if err := s.Delete(dir, "profile.a7p"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "profile.a7p")); !os.IsNotExist(err) {
+		t.Errorf("profile.a7p should no longer exist, stat err = %v", err)
+	}
+}
+
+func TestStoreAtomicWriteLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	s := New()
+
+	if err := s.AtomicWrite(dir, "profile.a7p", Frame([]byte("content"))); err != nil {
+		t.Fatalf("AtomicWrite: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "profile.a7p" {
+		t.Fatalf("dir contents = %+v, want only profile.a7p", entries)
+	}
+}
+
+func TestListETagChangesWithContent(t *testing.T) {
+	dir := t.TempDir()
+	s := New()
+
+	if err := s.Write(dir, "profile.a7p", []byte("v1")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	files, err := s.List(dir)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	etag1, err := s.ListETag(dir, files)
+	if err != nil {
+		t.Fatalf("ListETag: %v", err)
+	}
+
+	if err := s.Write(dir, "profile.a7p", []byte("v2")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	files, err = s.List(dir)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	etag2, err := s.ListETag(dir, files)
+	if err != nil {
+		t.Fatalf("ListETag: %v", err)
+	}
+
+	if etag1 == etag2 {
+		t.Error("ListETag should change when a file's content changes")
+	}
+}
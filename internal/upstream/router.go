@@ -0,0 +1,72 @@
+// Package upstream implements a routing table mapping full-URL regexps to
+// handlers, so new endpoints plug in by adding a Route instead of growing
+// a switch over r.Method inside one handler.
+package upstream
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// Route matches requests whose method equals Method (or any method, if
+// Method is empty) and whose path matches Pattern.
+type Route struct {
+	Method  string
+	Pattern *regexp.Regexp
+	Handler http.Handler
+}
+
+// Router dispatches each request to the first Route that matches it, in
+// registration order: routes with an explicit Method are tried first, and
+// a path that matches one of their patterns but not by method gets 405
+// rather than falling through to an unrelated route. Routes with no
+// Method (e.g. a static-file catch-all) are tried only once no
+// method-specific route's pattern matched at all, falling back to 404.
+type Router struct {
+	routes []Route
+}
+
+// NewRouter creates an empty Router.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Add registers a handler for requests whose path matches pattern
+// (a regexp anchored against the full URL path) and whose method equals
+// method, or any method if method is empty. It returns the Router so
+// calls can be chained.
+func (rt *Router) Add(method, pattern string, handler http.Handler) *Router {
+	rt.routes = append(rt.routes, Route{
+		Method:  method,
+		Pattern: regexp.MustCompile(pattern),
+		Handler: handler,
+	})
+	return rt
+}
+
+// ServeHTTP implements http.Handler.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	methodMismatch := false
+	for _, route := range rt.routes {
+		if route.Method == "" || !route.Pattern.MatchString(r.URL.Path) {
+			continue
+		}
+		if route.Method == r.Method {
+			route.Handler.ServeHTTP(w, r)
+			return
+		}
+		methodMismatch = true
+	}
+	if methodMismatch {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	for _, route := range rt.routes {
+		if route.Method == "" && route.Pattern.MatchString(r.URL.Path) {
+			route.Handler.ServeHTTP(w, r)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
@@ -0,0 +1,100 @@
+package upstream
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func handlerReturning(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+}
+
+func TestRouterDispatchesByMethodAndPattern(t *testing.T) {
+	router := NewRouter()
+	router.Add(http.MethodGet, `^/files$`, handlerReturning("get-files"))
+	router.Add(http.MethodPut, `^/files$`, handlerReturning("put-files"))
+	router.Add("", `^/.*$`, handlerReturning("static"))
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	get := func(method, path string) string {
+		req, err := http.NewRequest(method, srv.URL+path, nil)
+		if err != nil {
+			t.Fatalf("NewRequest: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("reading body: %v", err)
+		}
+		return string(body)
+	}
+
+	if got := get(http.MethodGet, "/files"); got != "get-files" {
+		t.Errorf("GET /files = %q, want %q", got, "get-files")
+	}
+	if got := get(http.MethodPut, "/files"); got != "put-files" {
+		t.Errorf("PUT /files = %q, want %q", got, "put-files")
+	}
+	if got := get(http.MethodGet, "/index.html"); got != "static" {
+		t.Errorf("GET /index.html = %q, want %q", got, "static")
+	}
+}
+
+func TestRouterReturns405InsteadOfFallingThroughToCatchAll(t *testing.T) {
+	router := NewRouter()
+	router.Add(http.MethodGet, `^/files$`, handlerReturning("get-files"))
+	router.Add(http.MethodPut, `^/files$`, handlerReturning("put-files"))
+	router.Add("", `^/.*$`, handlerReturning("static"))
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPatch, srv.URL+"/files", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("PATCH /files status = %d, want 405, not the catch-all route", resp.StatusCode)
+	}
+}
+
+func TestRouterFallsBackTo404(t *testing.T) {
+	router := NewRouter()
+	router.Add(http.MethodGet, `^/files$`, handlerReturning("get-files"))
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/files")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /files status = %d, want 200", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/nope")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /nope status = %d, want 404", resp.StatusCode)
+	}
+}